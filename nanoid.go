@@ -21,11 +21,14 @@
 package nanoid
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"io"
 	"math"
+	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 // DefaultAlphabet is a default alphabet.
@@ -60,12 +63,138 @@ var bufSlicePool = sync.Pool{
 // zeroString represents an empty string that does not contain any characters.
 var zeroString string
 
+// defaultPrefetchBufBytes is the default size, in bytes, of each refill
+// performed by a prefetchReader when WithPrefetch is given a bufBytes
+// value less than or equal to zero.
+const defaultPrefetchBufBytes = 4096
+
+// prefetchShard is one shard of a prefetchReader. Each shard owns its own
+// buffer and mutex, so concurrent callers that land on different shards do
+// not contend with each other.
+type prefetchShard struct {
+	mutex  sync.Mutex
+	source io.Reader
+	buf    []byte
+	pos    int
+	err    error
+}
+
+// fill reads bufBytes fresh random bytes from the shard's source, growing
+// the usable portion of the buffer back out if a previous fill left it
+// truncated.
+func (s *prefetchShard) fill(bufBytes int) {
+	if cap(s.buf) < bufBytes {
+		s.buf = make([]byte, bufBytes)
+	} else {
+		s.buf = s.buf[:bufBytes]
+	}
+
+	filled, err := io.ReadFull(s.source, s.buf)
+	s.pos = 0
+	s.err = err
+	if filled < bufBytes {
+		// Only part of the buffer holds bytes actually read from the
+		// source; the remainder must never be served to callers.
+		s.buf = s.buf[:filled]
+	}
+}
+
+// read serves p from the shard's buffer, refilling from the source in
+// bufBytes-sized blocks whenever the buffer runs dry. An error from the
+// source is only surfaced once every byte read before it has been
+// consumed, so a short refill never hides bytes that were genuinely read.
+func (s *prefetchShard) read(p []byte, bufBytes int) (n int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for n < len(p) {
+		if s.pos == len(s.buf) {
+			if s.err != nil {
+				if n > 0 {
+					return n, nil
+				}
+				err, s.err = s.err, nil
+				return n, err
+			}
+			s.fill(bufBytes)
+			if len(s.buf) == 0 {
+				if n > 0 {
+					return n, nil
+				}
+				err, s.err = s.err, nil
+				return n, err
+			}
+		}
+		c := copy(p[n:], s.buf[s.pos:])
+		n += c
+		s.pos += c
+	}
+	return n, nil
+}
+
+// prefetchReader wraps an io.Reader and serves Read calls out of large,
+// pre-filled buffers instead of issuing one underlying read per call. It
+// is sharded across runtime.GOMAXPROCS(0) shards, keyed by a round-robin
+// counter, so that a single mutex does not become a point of contention
+// under highly concurrent workloads.
+type prefetchReader struct {
+	source   io.Reader
+	bufBytes int
+	shards   []*prefetchShard
+	next     uint32
+}
+
+// newPrefetchReader creates a prefetchReader that refills bufBytes bytes
+// at a time from source. If bufBytes is less than or equal to zero,
+// defaultPrefetchBufBytes is used instead.
+func newPrefetchReader(source io.Reader, bufBytes int) *prefetchReader {
+	if bufBytes <= 0 {
+		bufBytes = defaultPrefetchBufBytes
+	}
+
+	shardCount := runtime.GOMAXPROCS(0)
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	shards := make([]*prefetchShard, shardCount)
+	for index := range shards {
+		shards[index] = &prefetchShard{source: source}
+	}
+
+	return &prefetchReader{
+		source:   source,
+		bufBytes: bufBytes,
+		shards:   shards,
+	}
+}
+
+// Read implements io.Reader by delegating to one of the reader's shards.
+func (p *prefetchReader) Read(b []byte) (n int, err error) {
+	shard := p.shards[atomic.AddUint32(&p.next, 1)%uint32(len(p.shards))]
+	return shard.read(b, p.bufBytes)
+}
+
 // Reader is a reader for generating Nano IDs and has implemented the
 // io.Reader interface.
 type Reader struct {
-	rander   io.Reader
-	alphabet string
-	mask     int
+	rander           io.Reader
+	prefetchReader   *prefetchReader
+	prefetchBufBytes int
+	prefetchWanted   bool
+	rateStrategy     RateStrategy
+	alphabet         string
+	mask             int
+}
+
+// source returns the io.Reader that should actually be read from to
+// obtain random bytes: the prefetch buffer if WithPrefetch was used,
+// otherwise the configured rand reader directly.
+func (r *Reader) source() io.Reader {
+	if r.prefetchReader != nil {
+		return r.prefetchReader
+	}
+	return r.rander
 }
 
 // initializeMask initializes the mask using the alphabet. The caller must
@@ -104,13 +233,45 @@ func (r *Reader) getRandomSize(size int) int {
 // Read generates a Nano ID using the alphabet and stores it to the given
 // byte slice, then returns the actual number of bytes generated and any
 // errors encountered.
+//
+// If the reader is configured with WithRateLimit, Read never blocks: it
+// returns ErrRateLimited immediately when the strategy rejects the call.
+// Use ReadContext instead to wait for the strategy to admit the request.
 func (r *Reader) Read(p []byte) (n int, err error) {
 	if len(p) == 0 {
 		return 0, io.ErrShortBuffer
 	}
 
+	if r.rateStrategy != nil && !r.rateStrategy.Allow(1) {
+		return 0, ErrRateLimited
+	}
+
+	return r.generate(p)
+}
+
+// ReadContext behaves like Read, except that when the reader is configured
+// with WithRateLimit, it blocks on the rate strategy's Wait until the
+// request is admitted, ctx is done, or the strategy otherwise declines to
+// wait, instead of immediately returning ErrRateLimited.
+func (r *Reader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	if r.rateStrategy != nil {
+		if err = r.rateStrategy.Wait(ctx, 1); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.generate(p)
+}
+
+// generate fills p with one Nano ID using the reader's alphabet, without
+// applying any rate limiting. It is shared by Read and ReadContext.
+func (r *Reader) generate(p []byte) (n int, err error) {
 	if len(r.alphabet) == 0 {
-		_, err = r.rander.Read(p)
+		_, err = io.ReadFull(r.source(), p)
 		if err != nil {
 			return 0, err
 		}
@@ -131,7 +292,7 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 	}
 
 	for {
-		_, err = r.rander.Read(random)
+		_, err = io.ReadFull(r.source(), random)
 		if err != nil {
 			return n, err
 		}
@@ -148,6 +309,164 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 	}
 }
 
+// ReadBatch generates one Nano ID into each byte slice in ids and returns
+// the number of IDs successfully filled, along with any error encountered.
+//
+// Unlike calling Read once per ID, ReadBatch computes the total amount of
+// random data required for every ID up front and draws it from the rander
+// in as few reads as rejection sampling allows, which avoids the per-ID
+// buffer-pool and syscall overhead of bulk workloads such as seeding a
+// database. If the rander fails partway through, ReadBatch returns the
+// number of IDs it had already filled completely along with the error,
+// matching the io.Reader contract.
+//
+// If the reader is configured with WithRateLimit, ReadBatch never blocks:
+// it returns ErrRateLimited immediately when the strategy rejects the
+// call. Use ReadBatchContext instead to wait for the strategy to admit
+// the request.
+func (r *Reader) ReadBatch(ids [][]byte) (filled int, err error) {
+	total, err := validateBatch(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.rateStrategy != nil && !r.rateStrategy.Allow(len(ids)) {
+		return 0, ErrRateLimited
+	}
+
+	return r.generateBatch(ids, total)
+}
+
+// ReadBatchContext behaves like ReadBatch, except that when the reader is
+// configured with WithRateLimit, it blocks on the rate strategy's Wait
+// until the request is admitted, ctx is done, or the strategy otherwise
+// declines to wait, instead of immediately returning ErrRateLimited.
+func (r *Reader) ReadBatchContext(ctx context.Context, ids [][]byte) (filled int, err error) {
+	total, err := validateBatch(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.rateStrategy != nil {
+		if err = r.rateStrategy.Wait(ctx, len(ids)); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.generateBatch(ids, total)
+}
+
+// validateBatch checks that ids is non-empty and every ID in it has room
+// for at least one byte, and returns their combined length. It is shared
+// by ReadBatch and ReadBatchContext.
+func validateBatch(ids [][]byte) (total int, err error) {
+	if len(ids) == 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	for _, id := range ids {
+		if len(id) == 0 {
+			return 0, io.ErrShortBuffer
+		}
+		total += len(id)
+	}
+	return total, nil
+}
+
+// generateBatch fills ids with Nano IDs using the reader's alphabet,
+// without applying any rate limiting. total must be the combined length
+// of ids, as returned by validateBatch. It is shared by ReadBatch and
+// ReadBatchContext.
+func (r *Reader) generateBatch(ids [][]byte, total int) (filled int, err error) {
+	if len(r.alphabet) == 0 {
+		var random []byte
+		if total <= bufSliceSize {
+			pointer := bufSlicePool.Get().(*[]byte)
+			defer bufSlicePool.Put(pointer)
+			random = (*pointer)[:total]
+		} else {
+			random = make([]byte, total)
+		}
+
+		nr, rerr := io.ReadFull(r.source(), random)
+		pos := 0
+		for _, id := range ids {
+			if pos+len(id) > nr {
+				break
+			}
+			for index := range id {
+				id[index] = DefaultAlphabet[random[pos+index]&63]
+			}
+			pos += len(id)
+			filled++
+		}
+		return filled, rerr
+	}
+
+	var random []byte
+	size := r.getRandomSize(total)
+	if size <= bufSliceSize {
+		pointer := bufSlicePool.Get().(*[]byte)
+		defer bufSlicePool.Put(pointer)
+		random = (*pointer)[:size]
+	} else {
+		random = make([]byte, size)
+	}
+
+	n := 0
+	for filled < len(ids) {
+		_, err = io.ReadFull(r.source(), random)
+		if err != nil {
+			return filled, err
+		}
+		for index := range random {
+			position := random[index] & byte(r.mask)
+			if position < byte(len(r.alphabet)) {
+				ids[filled][n] = r.alphabet[position]
+				n++
+				if n == len(ids[filled]) {
+					filled++
+					n = 0
+					if filled == len(ids) {
+						return filled, nil
+					}
+				}
+			}
+		}
+	}
+	return filled, nil
+}
+
+// NewBatch returns count new Nano IDs, each of the given size, generated
+// using the reader's alphabet, and any error encountered.
+//
+// Compared to calling the reader in a loop, NewBatch draws all of the
+// random bytes it needs in as few reads as possible and fills every ID in
+// a single pass. If generation fails partway through, NewBatch returns
+// the IDs it had already filled along with the error.
+func (r *Reader) NewBatch(count, size int) (ids []string, err error) {
+	if count < 1 {
+		return nil, errors.New("count is too small")
+	}
+	if size < 1 {
+		return nil, errors.New("size is too small")
+	}
+
+	bufs := make([][]byte, count)
+	backing := make([]byte, count*size)
+	for index := range bufs {
+		bufs[index] = backing[index*size : (index+1)*size]
+	}
+
+	filled, err := r.ReadBatch(bufs)
+
+	ids = make([]string, filled)
+	for index := 0; index < filled; index++ {
+		ids[index] = string(bufs[index])
+	}
+	return ids, err
+}
+
 // ReaderOption is an option for the Nano ID reader. See the comments
 // section of the Reader structure for details.
 type ReaderOption func(r *Reader) error
@@ -183,6 +502,40 @@ func WithRandReader(rander io.Reader) ReaderOption {
 	}
 }
 
+// WithPrefetch wraps the reader's configured rand reader with an internal
+// buffer that is refilled bufBytes bytes at a time, amortizing crypto/rand
+// syscalls and lock contention across many generated Nano IDs. If bufBytes
+// is less than or equal to zero, a default of 4 KiB is used.
+//
+// The buffer is sharded across GOMAXPROCS shards, so concurrent callers
+// usually land on independent locks instead of contending for one.
+//
+// The prefetch buffer wraps whichever rand reader is configured once every
+// option has run, so WithPrefetch may be combined with WithRandReader in
+// either order.
+func WithPrefetch(bufBytes int) ReaderOption {
+	return func(r *Reader) error {
+		r.prefetchWanted = true
+		r.prefetchBufBytes = bufBytes
+		return nil
+	}
+}
+
+// WithRateLimit enforces strategy's limit on the number of Nano IDs that
+// may be generated per second. Read rejects immediately with
+// ErrRateLimited once the strategy's Allow declines a call; ReadContext
+// instead blocks on the strategy's Wait until it is admitted or ctx is
+// done.
+func WithRateLimit(strategy RateStrategy) ReaderOption {
+	return func(r *Reader) error {
+		if strategy == nil {
+			return errors.New("nil rate strategy")
+		}
+		r.rateStrategy = strategy
+		return nil
+	}
+}
+
 // NewReader creates and returns a reader for generating Nano IDs.
 func NewReader(options ...ReaderOption) (r io.Reader, err error) {
 	reader := &Reader{
@@ -194,6 +547,10 @@ func NewReader(options ...ReaderOption) (r io.Reader, err error) {
 			return nil, err
 		}
 	}
+
+	if reader.prefetchWanted {
+		reader.prefetchReader = newPrefetchReader(reader.rander, reader.prefetchBufBytes)
+	}
 	return reader, nil
 }
 
@@ -207,6 +564,33 @@ var defaultReader = &Reader{
 // errors encountered.
 func Read(p []byte) (n int, err error) { return defaultReader.Read(p) }
 
+// ReadContext generates a Nano ID using the default reader, waiting on its
+// configured rate strategy, if any, instead of rejecting immediately. See
+// Reader.ReadContext for details.
+func ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	return defaultReader.ReadContext(ctx, p)
+}
+
+// ReadBatch generates one Nano ID into each byte slice in ids using the
+// default alphabet, and returns the number of IDs successfully filled
+// along with any error encountered. See Reader.ReadBatch for details.
+func ReadBatch(ids [][]byte) (filled int, err error) { return defaultReader.ReadBatch(ids) }
+
+// ReadBatchContext generates one Nano ID into each byte slice in ids using
+// the default reader, waiting on its configured rate strategy, if any,
+// instead of rejecting immediately. See Reader.ReadBatchContext for
+// details.
+func ReadBatchContext(ctx context.Context, ids [][]byte) (filled int, err error) {
+	return defaultReader.ReadBatchContext(ctx, ids)
+}
+
+// NewBatch returns count new Nano IDs, each of the given size, generated
+// using the default alphabet, and any error encountered. See
+// Reader.NewBatch for details.
+func NewBatch(count, size int) (ids []string, err error) {
+	return defaultReader.NewBatch(count, size)
+}
+
 // NewWithSize returns the new Nano ID generated using the default alphabet
 // and any errors encountered. The size of the generated Nano ID depends on
 // the given size.
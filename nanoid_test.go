@@ -49,6 +49,56 @@ func BenchmarkNew(b *testing.B) {
 	})
 }
 
+func BenchmarkReadPrefetch(b *testing.B) {
+	r, err := NewReader(WithPrefetch(4096))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(21)
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		buf := make([]byte, 21)
+		for p.Next() {
+			_, _ = r.Read(buf)
+		}
+	})
+}
+
+func BenchmarkNewBatch(b *testing.B) {
+	b.SetBytes(21 * 10000)
+	b.ResetTimer()
+	for index := 0; index < b.N; index++ {
+		_, _ = NewBatch(10000, 21)
+	}
+}
+
+func BenchmarkNewLoop(b *testing.B) {
+	b.SetBytes(21 * 10000)
+	b.ResetTimer()
+	for index := 0; index < b.N; index++ {
+		for count := 0; count < 10000; count++ {
+			_, _ = New()
+		}
+	}
+}
+
+func BenchmarkReadCustomAlphabetPrefetch(b *testing.B) {
+	r, err := NewReader(WithAlphabet(customAlphabet), WithPrefetch(4096))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(21)
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		buf := make([]byte, 21)
+		for p.Next() {
+			_, _ = r.Read(buf)
+		}
+	})
+}
+
 func newTestReader(t *testing.T, opts ...ReaderOption) *Reader {
 	r, err := NewReader(opts...)
 	assert.NoError(t, err, "Unexpected error")
@@ -123,6 +173,54 @@ func TestWithRandReader(t *testing.T) {
 	assert.Error(t, err, "Unexpected nil error")
 }
 
+func TestWithPrefetch(t *testing.T) {
+	rander := newCustomRandReader(1024)
+	reader := newTestReader(t, WithRandReader(rander), WithPrefetch(64))
+	assert.NotNil(t, reader.prefetchReader, "Unexpected nil prefetch reader")
+	assert.Equal(t, 64, reader.prefetchReader.bufBytes, "Unexpected prefetch buffer size")
+
+	buf := make([]byte, 21)
+	nr, err := reader.Read(buf)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(buf), nr, "Unexpected read size")
+
+	defaulted := newTestReader(t, WithPrefetch(0))
+	assert.Equal(t, defaultPrefetchBufBytes, defaulted.prefetchReader.bufBytes, "Unexpected default prefetch buffer size")
+}
+
+func TestWithPrefetchBeforeWithRandReader(t *testing.T) {
+	rander := newCustomRandReader(1024)
+	reader := newTestReader(t, WithPrefetch(64), WithRandReader(rander))
+	assert.Equal(t, rander, reader.rander, "Unexpected rander")
+	assert.Equal(t, rander, reader.prefetchReader.shards[0].source, "Unexpected prefetch source")
+}
+
+func TestPrefetchReaderRead(t *testing.T) {
+	rander := newCustomRandReader(256)
+	pr := newPrefetchReader(rander, 64)
+
+	buf := make([]byte, 256)
+	n, err := pr.Read(buf)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(buf), n, "Unexpected read size")
+}
+
+func TestPrefetchReaderPartialEOF(t *testing.T) {
+	rander := newCustomRandReader(10)
+	pr := newPrefetchReader(rander, 64)
+	// Pin the reader to one shard so both reads see the same buffer and its
+	// deferred error; other shards would refill from the source on their own.
+	pr.shards = pr.shards[:1]
+
+	buf := make([]byte, 10)
+	n, err := pr.Read(buf)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(buf), n, "Unexpected read size")
+
+	_, err = pr.Read(buf)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "Unexpected error")
+}
+
 func TestReaderInitializeMask(t *testing.T) {
 	reader := newTestReader(t, WithAlphabet(customAlphabet))
 	assert.Equal(t, 63, reader.mask, "Unexpected mask")
@@ -189,6 +287,130 @@ func TestReaderCustomAlphabetRead(t *testing.T) {
 	assert.Error(t, err, "Unexpected nil error")
 }
 
+func TestReaderReadBatch(t *testing.T) {
+	rander := newCustomRandReader(256)
+	reader := newTestReader(t, WithRandReader(rander))
+
+	ids := make([][]byte, 4)
+	for index := range ids {
+		ids[index] = make([]byte, 21)
+	}
+
+	filled, err := reader.ReadBatch(ids)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(ids), filled, "Unexpected filled count")
+	for _, id := range ids {
+		assert.Len(t, id, 21, "Unexpected id size")
+	}
+
+	_, err = reader.ReadBatch(nil)
+	assert.Error(t, err, "Unexpected nil error")
+
+	_, err = reader.ReadBatch([][]byte{{}})
+	assert.Error(t, err, "Unexpected nil error")
+}
+
+// halfRandReader never fills a Read call past half of the requested
+// length plus one byte, forcing callers to retry across multiple calls to
+// read a full buffer.
+type halfRandReader struct {
+	data []byte
+}
+
+func (r *halfRandReader) Read(p []byte) (n int, err error) {
+	want := len(p)/2 + 1
+	if want > len(p) {
+		want = len(p)
+	}
+	n = copy(p[:want], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReaderReadBatchShortReads(t *testing.T) {
+	rander := &halfRandReader{data: newCustomRandReader(256).data}
+	reader := newTestReader(t, WithRandReader(rander))
+
+	ids := make([][]byte, 4)
+	for index := range ids {
+		ids[index] = make([]byte, 21)
+	}
+
+	filled, err := reader.ReadBatch(ids)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(ids), filled, "Unexpected filled count")
+	for _, id := range ids {
+		for _, b := range id {
+			assert.NotZero(t, b, "Unexpected unfilled byte from a short read")
+		}
+	}
+}
+
+func TestReaderReadBatchCustomAlphabet(t *testing.T) {
+	rander := newCustomRandReader(1024)
+	reader := newTestReader(t, WithRandReader(rander), WithAlphabet(customAlphabet))
+
+	ids := make([][]byte, 4)
+	for index := range ids {
+		ids[index] = make([]byte, 21)
+	}
+
+	filled, err := reader.ReadBatch(ids)
+	assert.NoError(t, err, "Unexpected read error")
+	assert.Equal(t, len(ids), filled, "Unexpected filled count")
+	assert.Equal(t, customAlphabet[:21], string(ids[0]), "Unexpected read data")
+
+	rander.data = nil
+	_, err = reader.ReadBatch(ids)
+	assert.Error(t, err, "Unexpected nil error")
+}
+
+func TestReaderNewBatch(t *testing.T) {
+	reader := newTestReader(t)
+
+	ids, err := reader.NewBatch(10, 21)
+	assert.NoError(t, err, "Unexpected error")
+	assert.Len(t, ids, 10, "Unexpected id count")
+	for _, id := range ids {
+		assert.Len(t, id, 21, "Unexpected id size")
+	}
+
+	_, err = reader.NewBatch(0, 21)
+	assert.Error(t, err, "Unexpected nil error")
+
+	_, err = reader.NewBatch(10, 0)
+	assert.Error(t, err, "Unexpected nil error")
+}
+
+func TestReadBatch(t *testing.T) {
+	defaultReader.rander = rand.Reader
+
+	ids := make([][]byte, 4)
+	for index := range ids {
+		ids[index] = make([]byte, 21)
+	}
+
+	filled, err := ReadBatch(ids)
+	assert.NoError(t, err, "Unexpected error")
+	assert.Equal(t, len(ids), filled, "Unexpected filled count")
+}
+
+func TestNewBatch(t *testing.T) {
+	defaultReader.rander = rand.Reader
+
+	ids, err := NewBatch(10, 21)
+	assert.NoError(t, err, "Unexpected error")
+	assert.Len(t, ids, 10, "Unexpected id count")
+	for _, id := range ids {
+		assert.Equal(t, 21, len(id), "Unexpected id size")
+	}
+
+	defaultReader.rander = &customRandReader{}
+	ids, err = NewBatch(10, 21)
+	assert.Error(t, err, "Unexpected nil error")
+	assert.Len(t, ids, 0, "Unexpected id count")
+}
+
 func TestNewWithSize(t *testing.T) {
 	defaultReader.rander = rand.Reader
 
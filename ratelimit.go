@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Handle
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nanoid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned by Reader.Read when a configured RateStrategy
+// has rejected the request to generate more Nano IDs.
+var ErrRateLimited = errors.New("nanoid: rate limited")
+
+// RateStrategy limits how many Nano IDs may be generated over time. It is
+// used by WithRateLimit to throttle a Reader: Reader.Read calls Allow and
+// rejects immediately, while Reader.ReadContext calls Wait and blocks.
+//
+// Wait blocks until n IDs are permitted to be generated, ctx is done, or
+// the strategy determines the wait would exceed any deadline carried by
+// ctx, whichever happens first. Allow reports, without blocking, whether
+// n IDs may be generated right now.
+type RateStrategy interface {
+	Wait(ctx context.Context, n int) error
+	Allow(n int) bool
+}
+
+// LocalRateStrategy is a RateStrategy that enforces a maximum IDs-per-second
+// limit independently in each process, backed by golang.org/x/time/rate.
+type LocalRateStrategy struct {
+	limiter *rate.Limiter
+}
+
+// NewLocalRateStrategy creates a LocalRateStrategy that allows up to
+// ratePerSecond IDs per second on average, with bursts of up to burst IDs.
+func NewLocalRateStrategy(ratePerSecond float64, burst int) *LocalRateStrategy {
+	return &LocalRateStrategy{
+		limiter: rate.NewLimiter(rate.Limit(ratePerSecond), burst),
+	}
+}
+
+// Wait implements RateStrategy.
+func (s *LocalRateStrategy) Wait(ctx context.Context, n int) error {
+	return s.limiter.WaitN(ctx, n)
+}
+
+// Allow implements RateStrategy.
+func (s *LocalRateStrategy) Allow(n int) bool {
+	return s.limiter.AllowN(time.Now(), n)
+}
+
+// ReplicaCountFn reports the current number of replicas sharing a
+// cluster-wide rate limit. See GlobalRateStrategy for how it is used.
+type ReplicaCountFn func() int
+
+// GlobalRateStrategy is a RateStrategy that approximates a cluster-wide
+// IDs-per-second limit by dividing it across the current number of
+// replicas, as reported by its ReplicaCountFn, and enforcing the resulting
+// per-replica share locally. This mirrors the distributor pattern used by
+// systems such as Loki/Cortex: no cross-node coordination is required, at
+// the cost of the limit only being as accurate as the last observed
+// replica count.
+//
+// If the ReplicaCountFn returns 0, the strategy falls back to enforcing the full
+// global limit locally, on the assumption that it is the only replica.
+type GlobalRateStrategy struct {
+	globalRatePerSecond float64
+	burst               int
+	replicaCount        ReplicaCountFn
+
+	mutex    sync.Mutex
+	limiter  *rate.Limiter
+	replicas int
+}
+
+// NewGlobalRateStrategy creates a GlobalRateStrategy that enforces a
+// cluster-wide limit of globalRatePerSecond IDs per second, split evenly
+// across the replica count reported by replicaCount, with bursts of up to
+// burst IDs against the per-replica share. A nil replicaCount behaves the
+// same as one that always reports a single replica.
+func NewGlobalRateStrategy(globalRatePerSecond float64, burst int, replicaCount ReplicaCountFn) *GlobalRateStrategy {
+	if replicaCount == nil {
+		replicaCount = func() int { return 1 }
+	}
+
+	return &GlobalRateStrategy{
+		globalRatePerSecond: globalRatePerSecond,
+		burst:               burst,
+		replicaCount:        replicaCount,
+		limiter:             rate.NewLimiter(rate.Limit(globalRatePerSecond), burst),
+		replicas:            1,
+	}
+}
+
+// limiterShare returns the strategy's limiter, resizing its limit first if
+// the replica count has changed since the last call.
+func (s *GlobalRateStrategy) limiterShare() *rate.Limiter {
+	replicas := s.replicaCount()
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if replicas != s.replicas {
+		s.replicas = replicas
+		s.limiter.SetLimit(rate.Limit(s.globalRatePerSecond / float64(replicas)))
+	}
+	return s.limiter
+}
+
+// Wait implements RateStrategy.
+func (s *GlobalRateStrategy) Wait(ctx context.Context, n int) error {
+	return s.limiterShare().WaitN(ctx, n)
+}
+
+// Allow implements RateStrategy.
+func (s *GlobalRateStrategy) Allow(n int) bool {
+	return s.limiterShare().AllowN(time.Now(), n)
+}
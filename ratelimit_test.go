@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Handle
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nanoid
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRateStrategyBurst(t *testing.T) {
+	strategy := NewLocalRateStrategy(1, 2)
+
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.False(t, strategy.Allow(1), "Unexpected allow beyond burst")
+}
+
+func TestLocalRateStrategyWaitCancel(t *testing.T) {
+	strategy := NewLocalRateStrategy(1, 1)
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+
+	// The context is already past its deadline before Wait is called, so
+	// Wait must observe ctx.Err() directly instead of computing a delay.
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Millisecond))
+	defer cancel()
+
+	err := strategy.Wait(ctx, 1)
+	assert.Error(t, err, "Unexpected nil error")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Unexpected error")
+}
+
+func TestGlobalRateStrategyReplicaShare(t *testing.T) {
+	replicas := 4
+	strategy := NewGlobalRateStrategy(4, 1, func() int { return replicas })
+
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.False(t, strategy.Allow(1), "Unexpected allow beyond burst")
+	assert.Equal(t, 1.0, float64(strategy.limiter.Limit()), "Unexpected per-replica limit")
+}
+
+func TestGlobalRateStrategyZeroReplicasFallsBackToLocal(t *testing.T) {
+	strategy := NewGlobalRateStrategy(4, 2, func() int { return 0 })
+
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.False(t, strategy.Allow(1), "Unexpected allow beyond burst")
+}
+
+func TestGlobalRateStrategyNilReplicaCount(t *testing.T) {
+	strategy := NewGlobalRateStrategy(4, 2, nil)
+
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.True(t, strategy.Allow(1), "Unexpected rejection within burst")
+	assert.False(t, strategy.Allow(1), "Unexpected allow beyond burst")
+}
+
+type rejectRateStrategy struct{}
+
+func (rejectRateStrategy) Wait(ctx context.Context, n int) error { return ErrRateLimited }
+func (rejectRateStrategy) Allow(n int) bool                      { return false }
+
+func TestWithRateLimit(t *testing.T) {
+	reader := newTestReader(t, WithRateLimit(rejectRateStrategy{}))
+
+	buf := make([]byte, 21)
+	_, err := reader.Read(buf)
+	assert.ErrorIs(t, err, ErrRateLimited, "Unexpected error")
+
+	_, err = reader.ReadContext(context.Background(), buf)
+	assert.ErrorIs(t, err, ErrRateLimited, "Unexpected error")
+
+	err = WithRateLimit(nil)(nil)
+	assert.Error(t, err, "Unexpected nil error")
+}
+
+func TestWithRateLimitBatch(t *testing.T) {
+	reader := newTestReader(t, WithRateLimit(rejectRateStrategy{}))
+
+	ids := [][]byte{make([]byte, 21), make([]byte, 21)}
+	_, err := reader.ReadBatch(ids)
+	assert.ErrorIs(t, err, ErrRateLimited, "Unexpected error")
+
+	_, err = reader.ReadBatchContext(context.Background(), ids)
+	assert.ErrorIs(t, err, ErrRateLimited, "Unexpected error")
+}
+
+func TestReadBatchContextWaitsOnRateStrategy(t *testing.T) {
+	reader := newTestReader(t, WithRateLimit(NewLocalRateStrategy(2, 2)))
+
+	ids := [][]byte{make([]byte, 21), make([]byte, 21)}
+	_, err := reader.ReadBatchContext(context.Background(), ids)
+	assert.NoError(t, err, "Unexpected read error")
+
+	// The burst is now exhausted, so a context that is already past its
+	// deadline must surface the strategy's Wait error instead of blocking.
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Millisecond))
+	defer cancel()
+
+	_, err = reader.ReadBatchContext(ctx, ids)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Unexpected error")
+}
+
+func TestReadContextWaitsOnRateStrategy(t *testing.T) {
+	reader := newTestReader(t, WithRateLimit(NewLocalRateStrategy(1, 1)))
+
+	buf := make([]byte, 21)
+	_, err := reader.ReadContext(context.Background(), buf)
+	assert.NoError(t, err, "Unexpected read error")
+
+	// The burst is now exhausted, so a context that is already past its
+	// deadline must surface the strategy's Wait error instead of blocking.
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Millisecond))
+	defer cancel()
+
+	_, err = reader.ReadContext(ctx, buf)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Unexpected error")
+}